@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrAsyncHandlerClosed is returned by AsyncHandler.Handle once Close has
+// been called, instead of silently queuing a record the drain goroutine
+// has already stopped delivering.
+var ErrAsyncHandlerClosed = errors.New("logger: async handler is closed")
+
+// OverflowPolicy decides what AsyncHandler does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest evicts the head of the queue to make room for the new
+	// record.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the record that just arrived, keeping the
+	// queue as-is.
+	DropNewest
+	// Block makes the caller wait until the drain goroutine frees up
+	// space.
+	Block
+)
+
+// asyncItem pairs a record with the context it was logged under and the
+// handler it must ultimately reach, since AsyncHandler.Handle returns
+// before the record is delivered and WithAttrs/WithGroup derive handlers
+// with different `next` sinks that still share one queue.
+type asyncItem struct {
+	ctx  context.Context
+	r    slog.Record
+	next slog.Handler
+}
+
+// asyncState is the bounded queue and drain goroutine shared by an
+// AsyncHandler and every derivative WithAttrs/WithGroup produces from
+// it, so deriving a handler (as FromContext's Logger.With does) never
+// spawns a second queue or goroutine.
+type asyncState struct {
+	capacity int
+	policy   OverflowPolicy
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []asyncItem
+	closed bool
+	done   chan struct{}
+
+	enqueued       uint64
+	dropped        uint64
+	flushedBatches uint64
+}
+
+// AsyncHandler decouples the caller's goroutine from a slow sink (file,
+// syslog, network) by buffering records in a bounded queue and draining
+// them on a dedicated goroutine.
+type AsyncHandler struct {
+	state *asyncState
+	next  slog.Handler
+}
+
+// AsyncStats is a point-in-time snapshot of AsyncHandler counters.
+type AsyncStats struct {
+	Enqueued       uint64
+	Dropped        uint64
+	FlushedBatches uint64
+}
+
+// NewAsyncHandler wraps next so records handed to Handle are queued
+// instead of delivered synchronously. capacity <= 0 defaults to 1024.
+func NewAsyncHandler(next slog.Handler, capacity int, policy OverflowPolicy) *AsyncHandler {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+	st := &asyncState{capacity: capacity, policy: policy, done: make(chan struct{})}
+	st.cond = sync.NewCond(&st.mu)
+	go st.drain()
+	return &AsyncHandler{state: st, next: next}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle enqueues a clone of r, applying the configured OverflowPolicy
+// if the queue is already at capacity.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.state.enqueue(asyncItem{ctx: ctx, r: r.Clone(), next: h.next})
+}
+
+// WithAttrs derives a handler for a narrower `next` sink but keeps using
+// the parent's queue and drain goroutine.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{state: h.state, next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup derives a handler the same way WithAttrs does.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{state: h.state, next: h.next.WithGroup(name)}
+}
+
+func (st *asyncState) enqueue(item asyncItem) error {
+	st.mu.Lock()
+	if st.closed {
+		st.mu.Unlock()
+		return ErrAsyncHandlerClosed
+	}
+	for len(st.buf) >= st.capacity && !st.closed {
+		switch st.policy {
+		case DropOldest:
+			st.buf = st.buf[1:]
+			st.dropped++
+		case DropNewest:
+			st.dropped++
+			st.mu.Unlock()
+			return nil
+		default: // Block
+			st.cond.Wait()
+			continue
+		}
+		break
+	}
+	st.buf = append(st.buf, item)
+	st.enqueued++
+	st.cond.Signal()
+	st.mu.Unlock()
+	return nil
+}
+
+// drain runs on its own goroutine, delivering queued records to each
+// item's handler in batches until Close is called and the queue empties.
+func (st *asyncState) drain() {
+	defer close(st.done)
+	for {
+		st.mu.Lock()
+		for len(st.buf) == 0 && !st.closed {
+			st.cond.Wait()
+		}
+		if len(st.buf) == 0 && st.closed {
+			st.mu.Unlock()
+			return
+		}
+		batch := st.buf
+		st.buf = nil
+		st.cond.Broadcast() // wake any Block-ed writers
+		st.mu.Unlock()
+
+		for _, item := range batch {
+			_ = item.next.Handle(item.ctx, item.r)
+		}
+
+		st.mu.Lock()
+		st.flushedBatches++
+		st.mu.Unlock()
+	}
+}
+
+// AsyncStats returns a snapshot of the handler's enqueue/drop/flush
+// counters, shared with every handler derived from the same
+// NewAsyncHandler call.
+func (h *AsyncHandler) AsyncStats() AsyncStats {
+	st := h.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return AsyncStats{
+		Enqueued:       st.enqueued,
+		Dropped:        st.dropped,
+		FlushedBatches: st.flushedBatches,
+	}
+}
+
+// Flush blocks until the queue drains or ctx is canceled.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	st := h.state
+	for {
+		st.mu.Lock()
+		empty := len(st.buf) == 0
+		st.mu.Unlock()
+		if empty {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// Close flushes remaining records, stops the drain goroutine shared by
+// every handler derived from the same NewAsyncHandler call, and closes
+// this handler's next if it implements io.Closer.
+func (h *AsyncHandler) Close() error {
+	st := h.state
+	st.mu.Lock()
+	st.closed = true
+	st.cond.Broadcast()
+	st.mu.Unlock()
+	<-st.done
+
+	if closer, ok := h.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type noopHandler struct{}
+
+func (noopHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (noopHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h noopHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h noopHandler) WithGroup(string) slog.Handler           { return h }
+
+func TestAsyncHandlerWithAttrsSharesGoroutine(t *testing.T) {
+	h := NewAsyncHandler(noopHandler{}, 16, DropOldest)
+	defer h.Close()
+
+	runtime.Gosched()
+	before := runtime.NumGoroutine()
+
+	var derived slog.Handler = h
+	for i := 0; i < 200; i++ {
+		derived = derived.WithAttrs([]slog.Attr{slog.Int("i", i)})
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	runtime.Gosched()
+	after := runtime.NumGoroutine()
+
+	if after > before+1 {
+		t.Fatalf("200 WithAttrs calls leaked goroutines: before=%d after=%d", before, after)
+	}
+}
+
+func TestAsyncHandlerRejectsHandleAfterClose(t *testing.T) {
+	h := NewAsyncHandler(noopHandler{}, 16, DropOldest)
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := h.Handle(context.Background(), slog.Record{}); err != ErrAsyncHandlerClosed {
+			t.Fatalf("Handle after Close: got err %v, want ErrAsyncHandlerClosed", err)
+		}
+	}
+
+	if got := len(h.state.buf); got != 0 {
+		t.Fatalf("Handle after Close grew the buffer: len=%d, want 0", got)
+	}
+	if stats := h.AsyncStats(); stats.Dropped != 0 && stats.Enqueued != 0 {
+		t.Fatalf("unexpected stats after rejecting closed-handler writes: %+v", stats)
+	}
+}
@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ctxLoggerKey is the private context key WithContext/FromContext use to
+// carry the request-scoped Logger itself, rather than a bag of attrs,
+// since slog.Logger.Info and friends don't take a context.Context and
+// so can't re-derive attrs from one at call time.
+type ctxLoggerKey struct{}
+
+// WithContext derives a child Logger carrying attrs, in addition to any
+// already attached by a previous WithContext call on this context (or
+// seeded by CreateRequestLoggerMiddleware), and returns a copy of ctx
+// that FromContext(ctx) will resolve to that child.
+func WithContext(ctx context.Context, attrs ...slog.Attr) context.Context {
+	base := FromContext(ctx)
+	if base == nil {
+		return ctx
+	}
+	args := make([]any, len(attrs))
+	for i, a := range attrs {
+		args[i] = a
+	}
+	return context.WithValue(ctx, ctxLoggerKey{}, base.With(args...))
+}
+
+// FromContext returns the Logger attached to ctx by WithContext (or by
+// CreateRequestLoggerMiddleware), or the global Logger if none has been
+// attached.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(ctxLoggerKey{}).(*Logger); ok {
+		return l
+	}
+	return Get()
+}
+
+// With returns a Logger that behaves like ll but with args appended to
+// every record, the way slog.Logger.With does, while preserving the
+// fields WithContext/FromContext callers rely on.
+func (ll *Logger) With(args ...any) *Logger {
+	child := &Logger{
+		logsDict:   ll.logsDict,
+		mod:        ll.mod,
+		LoggerType: ll.LoggerType,
+		rotWriter:  ll.rotWriter,
+	}
+	child.Logger = ll.Logger.With(args...)
+	return child
+}
+
+// ContextKey pairs a context value key with the attribute name its value
+// should be logged under.
+type ContextKey struct {
+	CtxKey any
+	Attr   string
+}
+
+type traceIDCtxKey struct{}
+type spanIDCtxKey struct{}
+type userIDCtxKey struct{}
+
+// DefaultContextKeys is the set of request-scoped keys ContextHandler is
+// typically configured with: trace_id, span_id and user_id. request_id
+// is deliberately not one of these — CreateRequestLoggerMiddleware bakes
+// it into the Logger FromContext returns via With() instead, so it isn't
+// also read here, which would double it into every record logged with a
+// *Context call.
+var DefaultContextKeys = []ContextKey{
+	{CtxKey: traceIDCtxKey{}, Attr: "trace_id"},
+	{CtxKey: spanIDCtxKey{}, Attr: "span_id"},
+	{CtxKey: userIDCtxKey{}, Attr: "user_id"},
+}
+
+// WithUserID attaches a user id that ContextHandler logs as user_id.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDCtxKey{}, id)
+}
+
+// TraceContextExtractor, when set, lets ContextHandler pull trace/span
+// ids out of a context without this package taking a hard dependency on
+// go.opentelemetry.io/otel/trace. Callers that import OTel can set it
+// to a function built on trace.SpanContextFromContext, e.g.:
+//
+//	logger.TraceContextExtractor = func(ctx context.Context) (traceID, spanID string, ok bool) {
+//	    sc := trace.SpanContextFromContext(ctx)
+//	    if !sc.IsValid() {
+//	        return "", "", false
+//	    }
+//	    return sc.TraceID().String(), sc.SpanID().String(), true
+//	}
+var TraceContextExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+// ContextHandler wraps a slog.Handler and, on every Handle call, copies
+// the configured context keys (trace_id, span_id, user_id by default)
+// out of ctx and appends them to the record before delegating.
+type ContextHandler struct {
+	slog.Handler
+	keys []ContextKey
+}
+
+// NewContextHandler wraps h so records gain an attribute for every key in
+// keys that is present in the context passed to Handle. A nil keys
+// falls back to DefaultContextKeys.
+func NewContextHandler(h slog.Handler, keys ...ContextKey) *ContextHandler {
+	if len(keys) == 0 {
+		keys = DefaultContextKeys
+	}
+	return &ContextHandler{Handler: h, keys: keys}
+}
+
+func (c *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	haveTrace, haveSpan := false, false
+	for _, k := range c.keys {
+		v := ctx.Value(k.CtxKey)
+		if v == nil {
+			continue
+		}
+		r.AddAttrs(slog.Any(k.Attr, v))
+		switch k.CtxKey.(type) {
+		case traceIDCtxKey:
+			haveTrace = true
+		case spanIDCtxKey:
+			haveSpan = true
+		}
+	}
+	if TraceContextExtractor != nil && (!haveTrace || !haveSpan) {
+		if traceID, spanID, ok := TraceContextExtractor(ctx); ok {
+			if !haveTrace {
+				r.AddAttrs(slog.String("trace_id", traceID))
+			}
+			if !haveSpan {
+				r.AddAttrs(slog.String("span_id", spanID))
+			}
+		}
+	}
+	return c.Handler.Handle(ctx, r)
+}
+
+func (c *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: c.Handler.WithAttrs(attrs), keys: c.keys}
+}
+
+func (c *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: c.Handler.WithGroup(name), keys: c.keys}
+}
+
+// statusWriter records the status code and byte count an http.Handler
+// wrote, so the access log line below can report them.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+// CreateRequestLoggerMiddleware builds a request-scoped Logger the same
+// way CreateRequestLogger does, wraps its handler with a ContextHandler
+// so trace_id/span_id/user_id are picked up automatically by any
+// *Context slog call, and returns an http.Handler middleware that
+// generates a correlation id per request, echoes it back as the
+// X-Request-Id response header, seeds the request context so
+// logger.FromContext(ctx) resolves to a Logger with request_id already
+// attached via With(), and logs one access line with method, path,
+// status, bytes and latency.
+func CreateRequestLoggerMiddleware(mod Mod, logsDict Path) (*Logger, func(http.Handler) http.Handler) {
+	rl := CreateRequestLogger(mod, logsDict)
+	rl.setLogger(slog.New(NewContextHandler(rl.Logger.Handler())))
+
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := NewRequestID()
+			ctx := context.WithValue(r.Context(), ctxLoggerKey{}, rl)
+			ctx = WithContext(ctx, slog.String("request_id", id))
+			w.Header().Set("X-Request-Id", id)
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			// Use the request-scoped Logger WithContext attached above, so
+			// this line carries request_id too, without adding it again.
+			FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", sw.status),
+				slog.Int("bytes", sw.bytes),
+				slog.Duration("latency", time.Since(start)),
+			)
+		})
+	}
+	return rl, mw
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRequestLoggingMiddlewarePropagatesRequestID(t *testing.T) {
+	dir := t.TempDir()
+	rl, mw := CreateRequestLoggerMiddleware(ModProd, Path(dir+"/"))
+	defer rl.rotWriter.Close()
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handler log line")
+		// Deliberately never call Write/WriteHeader: exercises the
+		// implicit-200 path.
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	handler.ServeHTTP(rec, req)
+
+	if id := rec.Header().Get("X-Request-Id"); id == "" {
+		t.Fatal("expected X-Request-Id response header to be set")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("expected a log file in %s, err=%v entries=%v", dir, err, entries)
+	}
+	data, err := os.ReadFile(dir + "/" + entries[0].Name())
+	if err != nil {
+		t.Fatalf("reading log file: %v", err)
+	}
+	out := string(data)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines (handler + access), got %d: %q", len(lines), out)
+	}
+	if !strings.Contains(lines[0], `"request_id"`) {
+		t.Errorf("handler log line missing request_id: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"request_id"`) {
+		t.Errorf("access log line missing request_id: %s", lines[1])
+	}
+	if strings.Contains(lines[1], `"status":0`) {
+		t.Errorf("access log line recorded status 0 despite the handler never writing: %s", lines[1])
+	}
+	if !strings.Contains(lines[1], `"status":200`) {
+		t.Errorf("access log line should default to status 200, got: %s", lines[1])
+	}
+}
+
+func TestRequestLoggingMiddlewareDoesNotDuplicateRequestIDOnContextCalls(t *testing.T) {
+	dir := t.TempDir()
+	rl, mw := CreateRequestLoggerMiddleware(ModProd, Path(dir+"/"))
+	defer rl.rotWriter.Close()
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		FromContext(ctx).InfoContext(ctx, "info context line")
+		FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "log attrs line")
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	handler.ServeHTTP(rec, req)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("expected a log file in %s, err=%v entries=%v", dir, err, entries)
+	}
+	data, err := os.ReadFile(dir + "/" + entries[0].Name())
+	if err != nil {
+		t.Fatalf("reading log file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 log lines (2 handler + access), got %d: %q", len(lines), data)
+	}
+	for _, line := range lines[:2] {
+		if n := strings.Count(line, `"request_id"`); n != 1 {
+			t.Errorf("expected exactly one request_id key, got %d: %s", n, line)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// requestIDEncoding is the base32 hex alphabet used by xid and friends:
+// lowercase, no padding, sorts the same as the raw bytes it encodes.
+const requestIDEncoding = "0123456789abcdefghijklmnopqrstuv"
+
+var (
+	requestIDMachine = machineID()
+	requestIDPID     = uint32(os.Getpid())
+	requestIDCounter uint32
+)
+
+func machineID() [3]byte {
+	var buf [3]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		host, _ := os.Hostname()
+		for i := 0; i < len(host) && i < 3; i++ {
+			buf[i] = host[i]
+		}
+	}
+	return buf
+}
+
+// NewRequestID returns a 12-byte, time-sortable identifier in the style
+// of rs/xid: a 4-byte unix timestamp, a 3-byte machine id, a 2-byte
+// process id and a 3-byte rolling counter, base32-encoded to 20
+// characters. It never allocates beyond the returned string.
+func NewRequestID() string {
+	var raw [12]byte
+	binary.BigEndian.PutUint32(raw[0:4], uint32(time.Now().Unix()))
+	copy(raw[4:7], requestIDMachine[:])
+	raw[7] = byte(requestIDPID >> 8)
+	raw[8] = byte(requestIDPID)
+	n := atomic.AddUint32(&requestIDCounter, 1)
+	raw[9] = byte(n >> 16)
+	raw[10] = byte(n >> 8)
+	raw[11] = byte(n)
+	return encodeRequestID(raw)
+}
+
+// encodeRequestID base32-hex encodes 12 bytes into the 20-character
+// form xid uses, without the padding a stdlib base32.Encoding would add.
+func encodeRequestID(raw [12]byte) string {
+	dst := make([]byte, 20)
+	dst[0] = requestIDEncoding[raw[0]>>3]
+	dst[1] = requestIDEncoding[(raw[1]>>6)&0x1F|(raw[0]<<2)&0x1F]
+	dst[2] = requestIDEncoding[(raw[1]>>1)&0x1F]
+	dst[3] = requestIDEncoding[(raw[2]>>4)&0x1F|(raw[1]<<4)&0x1F]
+	dst[4] = requestIDEncoding[raw[3]>>7|(raw[2]<<1)&0x1F]
+	dst[5] = requestIDEncoding[(raw[3]>>2)&0x1F]
+	dst[6] = requestIDEncoding[(raw[4]>>5)&0x1F|(raw[3]<<3)&0x1F]
+	dst[7] = requestIDEncoding[raw[4]&0x1F]
+	dst[8] = requestIDEncoding[raw[5]>>3]
+	dst[9] = requestIDEncoding[(raw[6]>>6)&0x1F|(raw[5]<<2)&0x1F]
+	dst[10] = requestIDEncoding[(raw[6]>>1)&0x1F]
+	dst[11] = requestIDEncoding[(raw[7]>>4)&0x1F|(raw[6]<<4)&0x1F]
+	dst[12] = requestIDEncoding[raw[8]>>7|(raw[7]<<1)&0x1F]
+	dst[13] = requestIDEncoding[(raw[8]>>2)&0x1F]
+	dst[14] = requestIDEncoding[(raw[9]>>5)&0x1F|(raw[8]<<3)&0x1F]
+	dst[15] = requestIDEncoding[raw[9]&0x1F]
+	dst[16] = requestIDEncoding[raw[10]>>3]
+	dst[17] = requestIDEncoding[(raw[11]>>6)&0x1F|(raw[10]<<2)&0x1F]
+	dst[18] = requestIDEncoding[(raw[11]>>1)&0x1F]
+	dst[19] = requestIDEncoding[(raw[11]<<4)&0x1F]
+	return string(dst)
+}
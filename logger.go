@@ -1,7 +1,6 @@
 package logger
 
 import (
-	"fmt"
 	"github.com/lmittmann/tint"
 	"io"
 	"log/slog"
@@ -28,6 +27,12 @@ const (
 	DefaultRequestPath = "./logs/request_logs/"
 )
 
+// defaultRotationPolicy reproduces the historical behaviour: one file
+// per calendar day, kept forever, never compressed.
+var defaultRotationPolicy = RotationPolicy{
+	Pattern: "%Y-%m-%d.txt",
+}
+
 var (
 	instance *Logger
 	once     sync.Once
@@ -36,11 +41,10 @@ var (
 type Logger struct {
 	sync.RWMutex
 	*slog.Logger
-	logFile    *os.File
 	logsDict   Path
 	mod        Mod
 	LoggerType Type
-	today      time.Time
+	rotWriter  *RotatingFileWriter
 }
 
 // Get returns the singleton instance of Logger
@@ -65,7 +69,7 @@ func init() {
 	}
 
 	instance = build(sPath, mod, lType)
-	handler := selectHandler(mod, instance.logsDict)
+	handler := selectHandler(instance, mod, instance.logsDict)
 	instance.setLogger(slog.New(handler))
 	if mod == ModProd {
 		instance.watcher()
@@ -80,7 +84,6 @@ func build(logsDict Path, mod Mod, lType Type) *Logger {
 		logsDict:   logsDict,
 		mod:        mod,
 		LoggerType: lType,
-		today:      time.Now(),
 	}
 }
 
@@ -91,42 +94,58 @@ func (ll *Logger) setLogger(logger *slog.Logger) {
 	ll.Logger = logger
 }
 
-// watcher starts a background goroutine to handle daily log rotation
+// watcher starts a background goroutine that periodically gives the
+// rotating file writer a chance to roll over even when the process is
+// otherwise quiet (no writes to trigger a size/time check).
 func (ll *Logger) watcher() {
 	go func() {
 		for {
-			time.Sleep(1 * time.Hour)
+			time.Sleep(1 * time.Minute)
 			ll.RLock()
-			shouldRotate := ll.today.Day() != time.Now().Day()
+			w := ll.rotWriter
 			ll.RUnlock()
-			if shouldRotate {
-				ll.setLogger(slog.New(prodHandler(ll.logsDict)))
-				ll.Lock()
-				ll.today = time.Now()
-				ll.Unlock()
+			if w == nil {
+				continue
+			}
+			if err := w.MaybeRotate(); err != nil {
+				slog.Default().Error("logger: rotation failed", "error", err)
 			}
 		}
 	}()
 }
 
-// selectHandler returns the appropriate slog.Handler based on the mode
-func selectHandler(mod Mod, path Path) slog.Handler {
+// selectHandler returns the appropriate slog.Handler based on the mode,
+// wiring the Logger's rotWriter when running in ModProd.
+func selectHandler(ll *Logger, mod Mod, path Path) slog.Handler {
 	if mod == ModProd {
-		return prodHandler(path)
+		return prodHandler(ll, path, defaultRotationPolicy)
 	}
 	return devHandler()
 }
 
-// prodHandler creates a handler for production logging
-func prodHandler(path Path) slog.Handler {
-	file, err := openLogFile(path)
+// prodHandler creates a handler for production logging backed by a
+// RotatingFileWriter built from policy, and records the writer on ll so
+// Logger.watcher can drive time-based rotation. Records are scrubbed by
+// a RedactingHandler (DefaultRedactOptions) before they ever reach
+// stdout or the file, so the file/stdout sinks never see raw secrets.
+// Setting LOGGER_ASYNC=1 wraps the result in an AsyncHandler so slow
+// disk I/O never blocks the caller; rotation still happens safely
+// underneath since the writer, not the handler, is what gets swapped.
+func prodHandler(ll *Logger, path Path, policy RotationPolicy) slog.Handler {
+	w, err := NewRotatingFileWriter(path, policy)
 	if err != nil {
 		panic(err)
 	}
-	return slog.NewJSONHandler(io.MultiWriter(os.Stdout, file), &slog.HandlerOptions{
+	ll.rotWriter = w
+	var h slog.Handler = slog.NewJSONHandler(io.MultiWriter(os.Stdout, w), &slog.HandlerOptions{
 		AddSource: true,
 		Level:     slog.LevelDebug,
 	})
+	h = NewRedactingHandler(h, DefaultRedactOptions())
+	if os.Getenv("LOGGER_ASYNC") == "1" {
+		return NewAsyncHandler(h, 1024, DropOldest)
+	}
+	return h
 }
 
 // devHandler creates a handler for development logging
@@ -138,16 +157,6 @@ func devHandler() slog.Handler {
 	})
 }
 
-// openLogFile opens or creates a log file at the specified path
-func openLogFile(path Path) (*os.File, error) {
-	p := path.String() + fmt.Sprintf("%s.txt", time.Now().Format(time.DateOnly))
-	err := os.MkdirAll(path.String(), os.ModePerm)
-	if err != nil {
-		return nil, err
-	}
-	return os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-}
-
 // Empty Utility methods to check for empty values
 func (m Mod) Empty() bool     { return m == "" }
 func (t Type) Empty() bool    { return t == "" }
@@ -159,7 +168,22 @@ func CreateProdLogger(logsDict Path) {
 		logsDict = DefaultServicePath
 	}
 	logger := build(logsDict, ModProd, DefaultType)
-	handler := prodHandler(logsDict)
+	handler := prodHandler(logger, logsDict, defaultRotationPolicy)
+	logger.setLogger(slog.New(handler))
+	logger.watcher()
+	instance = logger
+	slog.SetDefault(logger.Logger)
+}
+
+// CreateProdLoggerWithRotation behaves like CreateProdLogger but lets
+// callers control size/age-based rotation, backup retention and
+// compression via policy instead of the one-file-per-day default.
+func CreateProdLoggerWithRotation(logsDict Path, policy RotationPolicy) {
+	if logsDict.Empty() {
+		logsDict = DefaultServicePath
+	}
+	logger := build(logsDict, ModProd, DefaultType)
+	handler := prodHandler(logger, logsDict, policy)
 	logger.setLogger(slog.New(handler))
 	logger.watcher()
 	instance = logger
@@ -181,7 +205,7 @@ func CreateRequestLogger(mod Mod, logsDict Path) *Logger {
 	logger := build(logsDict, mod, TypeRequest)
 	var handler slog.Handler
 	if mod == ModProd {
-		handler = prodHandler(logsDict)
+		handler = prodHandler(logger, logsDict, defaultRotationPolicy)
 		logger.watcher()
 	} else {
 		handler = devHandler()
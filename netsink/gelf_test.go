@@ -0,0 +1,118 @@
+package netsink
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeConn is a net.Conn that records every Write instead of sending
+// anything over the network.
+type fakeConn struct {
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func (c *fakeConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cp := append([]byte{}, p...)
+	c.writes = append(c.writes, cp)
+	return len(p), nil
+}
+
+func (c *fakeConn) Read([]byte) (int, error)         { return 0, nil }
+func (c *fakeConn) Close() error                     { return nil }
+func (c *fakeConn) LocalAddr() net.Addr              { return nil }
+func (c *fakeConn) RemoteAddr() net.Addr             { return nil }
+func (c *fakeConn) SetDeadline(time.Time) error      { return nil }
+func (c *fakeConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *fakeConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *fakeConn) writeCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.writes)
+}
+
+func newGelfHandlerWithFakeConn(chunkSize int) (*GelfHandler, *fakeConn) {
+	fc := &fakeConn{}
+	dial := func() (net.Conn, error) { return fc, nil }
+	h := &GelfHandler{
+		opts: GelfOptions{ChunkSize: chunkSize},
+		conn: newReconnectingWriter(dial, BackoffPolicy{}),
+	}
+	return h, fc
+}
+
+func TestGelfSendChunkedSendsSmallPayloadUnchunked(t *testing.T) {
+	h, fc := newGelfHandlerWithFakeConn(gelfDefaultChunkSize)
+	payload := []byte("small gelf payload")
+
+	if err := h.sendChunked(payload); err != nil {
+		t.Fatalf("sendChunked: %v", err)
+	}
+	if got := fc.writeCount(); got != 1 {
+		t.Fatalf("writeCount = %d, want 1", got)
+	}
+	if string(fc.writes[0]) != string(payload) {
+		t.Errorf("unchunked write = %q, want %q", fc.writes[0], payload)
+	}
+}
+
+func TestGelfSendChunkedSplitsLargePayload(t *testing.T) {
+	const chunkSize = 32
+	const headerSize = 12
+	h, fc := newGelfHandlerWithFakeConn(chunkSize)
+
+	chunkDataSize := chunkSize - headerSize
+	payload := make([]byte, chunkDataSize*3+5) // forces 4 chunks
+	for i := range payload {
+		payload[i] = byte(i % 251)
+	}
+
+	if err := h.sendChunked(payload); err != nil {
+		t.Fatalf("sendChunked: %v", err)
+	}
+
+	wantChunks := 4
+	if got := fc.writeCount(); got != wantChunks {
+		t.Fatalf("writeCount = %d, want %d", got, wantChunks)
+	}
+
+	var reassembled []byte
+	for i, chunk := range fc.writes {
+		if len(chunk) < headerSize {
+			t.Fatalf("chunk %d too short: %d bytes", i, len(chunk))
+		}
+		if chunk[0] != 0x1e || chunk[1] != 0x0f {
+			t.Errorf("chunk %d: bad magic bytes %x %x", i, chunk[0], chunk[1])
+		}
+		seq := int(chunk[10])
+		total := int(chunk[11])
+		if seq != i {
+			t.Errorf("chunk %d: seq byte = %d, want %d", i, seq, i)
+		}
+		if total != wantChunks {
+			t.Errorf("chunk %d: total byte = %d, want %d", i, total, wantChunks)
+		}
+		reassembled = append(reassembled, chunk[headerSize:]...)
+	}
+	if string(reassembled) != string(payload) {
+		t.Error("reassembled chunk data does not match original payload")
+	}
+}
+
+func TestGelfSendChunkedRejectsTooManyChunks(t *testing.T) {
+	const chunkSize = 32
+	const headerSize = 12
+	h, _ := newGelfHandlerWithFakeConn(chunkSize)
+
+	chunkDataSize := chunkSize - headerSize
+	payload := make([]byte, chunkDataSize*129) // needs 129 chunks, over the 128 max
+
+	if err := h.sendChunked(payload); err == nil {
+		t.Fatal("expected an error for a payload needing more than 128 chunks")
+	}
+}
@@ -0,0 +1,227 @@
+package netsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LokiOptions configures NewLokiHandler.
+type LokiOptions struct {
+	// PushURL is the Loki push endpoint, e.g.
+	// "http://loki:3100/loki/api/v1/push".
+	PushURL string
+	// Labels are static labels applied to every stream.
+	Labels map[string]string
+	// LabelKeys lists the attribute keys promoted to Loki labels;
+	// everything else is rendered into the log line.
+	LabelKeys []string
+	// BatchSize flushes once this many records have accumulated.
+	// Defaults to 100.
+	BatchSize int
+	// FlushInterval flushes on a timer regardless of batch size.
+	// Defaults to 2s.
+	FlushInterval time.Duration
+	Client        *http.Client
+	Backoff       BackoffPolicy
+}
+
+type lokiEntry struct {
+	labels string // pre-joined "k1=v1,k2=v2", used as the stream grouping key
+	pairs  map[string]string
+	ts     time.Time
+	line   string
+}
+
+// lokiState is the mutable batching/HTTP state shared by a LokiHandler
+// and every derivative WithAttrs/WithGroup produces from it, so a
+// derived handler's records still flush on the same timer.
+type lokiState struct {
+	opts    LokiOptions
+	allowed map[string]bool
+
+	mu       sync.Mutex
+	buf      []lokiEntry
+	closeCh  chan struct{}
+	closeOne sync.Once
+}
+
+// LokiHandler is a slog.Handler that batches records and pushes them to
+// Grafana Loki's /loki/api/v1/push JSON endpoint.
+type LokiHandler struct {
+	state *lokiState
+	attrSet
+}
+
+// NewLokiHandler builds a LokiHandler and starts its background flush
+// timer.
+func NewLokiHandler(opts LokiOptions) *LokiHandler {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 2 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	allowed := make(map[string]bool, len(opts.LabelKeys))
+	for _, k := range opts.LabelKeys {
+		allowed[k] = true
+	}
+	st := &lokiState{opts: opts, allowed: allowed, closeCh: make(chan struct{})}
+	go st.flushLoop()
+	return &LokiHandler{state: st}
+}
+
+func (h *LokiHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *LokiHandler) Handle(_ context.Context, r slog.Record) error {
+	st := h.state
+	pairs := make(map[string]string, len(st.opts.Labels))
+	for k, v := range st.opts.Labels {
+		pairs[k] = v
+	}
+	var line strings.Builder
+	line.WriteString(r.Message)
+
+	addAttr := func(a slog.Attr) {
+		if st.allowed[a.Key] {
+			pairs[a.Key] = a.Value.String()
+			return
+		}
+		fmt.Fprintf(&line, " %s=%s", a.Key, a.Value.String())
+	}
+	for _, a := range h.attrs {
+		addAttr(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(a)
+		return true
+	})
+
+	st.mu.Lock()
+	st.buf = append(st.buf, lokiEntry{labels: labelKey(pairs), pairs: pairs, ts: r.Time, line: line.String()})
+	full := len(st.buf) >= st.opts.BatchSize
+	st.mu.Unlock()
+
+	if full {
+		return st.flush()
+	}
+	return nil
+}
+
+func (h *LokiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LokiHandler{state: h.state, attrSet: h.withAttrs(attrs)}
+}
+
+func (h *LokiHandler) WithGroup(name string) slog.Handler {
+	return &LokiHandler{state: h.state, attrSet: h.withGroup(name)}
+}
+
+// Flush pushes any buffered records to Loki immediately.
+func (h *LokiHandler) Flush() error { return h.state.flush() }
+
+// Close flushes any remaining records and stops the flush timer. It
+// affects every handler derived from the same NewLokiHandler call.
+func (h *LokiHandler) Close() error {
+	h.state.closeOne.Do(func() { close(h.state.closeCh) })
+	return nil
+}
+
+func labelKey(pairs map[string]string) string {
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s,", k, pairs[k])
+	}
+	return b.String()
+}
+
+func (st *lokiState) flushLoop() {
+	t := time.NewTicker(st.opts.FlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			_ = st.flush()
+		case <-st.closeCh:
+			_ = st.flush()
+			return
+		}
+	}
+}
+
+// flush pushes any buffered records to Loki, retrying with exponential
+// backoff on failure.
+func (st *lokiState) flush() error {
+	st.mu.Lock()
+	batch := st.buf
+	st.buf = nil
+	st.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	streams := map[string]*lokiStream{}
+	for _, e := range batch {
+		s, ok := streams[e.labels]
+		if !ok {
+			s = &lokiStream{Stream: e.pairs}
+			streams[e.labels] = s
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.ts.UnixNano(), 10), e.line})
+	}
+	body := lokiPushBody{Streams: make([]*lokiStream, 0, len(streams))}
+	for _, s := range streams {
+		body.Streams = append(body.Streams, s)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 5; attempt++ {
+		if attempt > 0 {
+			time.Sleep(st.opts.Backoff.next(attempt - 1))
+		}
+		req, err := http.NewRequest(http.MethodPost, st.opts.PushURL, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := st.opts.Client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("netsink: loki push returned %s", resp.Status)
+	}
+	return lastErr
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPushBody struct {
+	Streams []*lokiStream `json:"streams"`
+}
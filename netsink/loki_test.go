@@ -0,0 +1,114 @@
+package netsink
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestLokiHandler(t *testing.T, opts LokiOptions) (*LokiHandler, func() []lokiPushBody) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []lokiPushBody
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body lokiPushBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding push body: %v", err)
+		}
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	opts.PushURL = srv.URL
+	opts.BatchSize = 1000
+	opts.FlushInterval = time.Hour
+	h := NewLokiHandler(opts)
+	t.Cleanup(func() { h.Close() })
+
+	return h, func() []lokiPushBody {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]lokiPushBody{}, bodies...)
+	}
+}
+
+func TestLokiHandlerPromotesLabelKeysAndLinesOtherAttrs(t *testing.T) {
+	h, bodies := newTestLokiHandler(t, LokiOptions{LabelKeys: []string{"service"}})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "request handled", 0)
+	r.AddAttrs(slog.String("service", "checkout"), slog.String("path", "/cart"))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	got := bodies()
+	if len(got) != 1 || len(got[0].Streams) != 1 {
+		t.Fatalf("got %+v, want exactly one stream in one push", got)
+	}
+	stream := got[0].Streams[0]
+	if stream.Stream["service"] != "checkout" {
+		t.Errorf("service label = %q, want checkout", stream.Stream["service"])
+	}
+	if _, ok := stream.Stream["path"]; ok {
+		t.Error("path should not be promoted to a label")
+	}
+	if len(stream.Values) != 1 {
+		t.Fatalf("got %d values, want 1", len(stream.Values))
+	}
+	line := stream.Values[0][1]
+	if !strings.Contains(line, "request handled") || !strings.Contains(line, "path=/cart") {
+		t.Errorf("line = %q, want message and path=/cart", line)
+	}
+}
+
+func TestLokiHandlerGroupsDistinctLabelSetsIntoSeparateStreams(t *testing.T) {
+	h, bodies := newTestLokiHandler(t, LokiOptions{LabelKeys: []string{"service"}})
+
+	for _, svc := range []string{"a", "a", "b"} {
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+		r.AddAttrs(slog.String("service", svc))
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	got := bodies()
+	if len(got) != 1 {
+		t.Fatalf("got %d pushes, want 1", len(got))
+	}
+	if len(got[0].Streams) != 2 {
+		t.Fatalf("got %d streams, want 2 (one per distinct service label)", len(got[0].Streams))
+	}
+	for _, s := range got[0].Streams {
+		if s.Stream["service"] == "a" && len(s.Values) != 2 {
+			t.Errorf("service=a stream has %d values, want 2", len(s.Values))
+		}
+		if s.Stream["service"] == "b" && len(s.Values) != 1 {
+			t.Errorf("service=b stream has %d values, want 1", len(s.Values))
+		}
+	}
+}
+
+func TestLabelKeyIsOrderIndependent(t *testing.T) {
+	a := labelKey(map[string]string{"b": "2", "a": "1"})
+	b := labelKey(map[string]string{"a": "1", "b": "2"})
+	if a != b {
+		t.Errorf("labelKey not order-independent: %q vs %q", a, b)
+	}
+}
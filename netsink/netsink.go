@@ -0,0 +1,150 @@
+// Package netsink provides slog.Handler sinks that ship records over
+// the network: syslog (RFC5424), GELF and Grafana Loki. Each handler
+// reconnects with exponential backoff and is meant to be wrapped in a
+// logger.AsyncHandler so a slow or unreachable endpoint never blocks the
+// caller.
+package netsink
+
+import (
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/heilart1n/logger"
+)
+
+// BackoffPolicy controls how long reconnect attempts wait between
+// tries. A zero value backs off from 100ms up to 30s.
+type BackoffPolicy struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (b BackoffPolicy) next(attempt int) time.Duration {
+	min := b.Min
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	max := b.Max
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	d := min << attempt
+	if d <= 0 || d > max {
+		d = max
+	}
+	return d
+}
+
+// DialFunc opens a fresh connection to a network sink.
+type DialFunc func() (net.Conn, error)
+
+// reconnectingWriter is an io.WriteCloser that lazily dials via dial and
+// reconnects with exponential backoff whenever a write fails.
+type reconnectingWriter struct {
+	mu      sync.Mutex
+	dial    DialFunc
+	backoff BackoffPolicy
+	conn    net.Conn
+	attempt int
+}
+
+func newReconnectingWriter(dial DialFunc, backoff BackoffPolicy) *reconnectingWriter {
+	return &reconnectingWriter{dial: dial, backoff: backoff}
+}
+
+func (w *reconnectingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if err := w.connectLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.conn.Write(p)
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		if cerr := w.connectLocked(); cerr == nil {
+			n, err = w.conn.Write(p)
+		}
+	}
+	if err == nil {
+		w.attempt = 0
+	}
+	return n, err
+}
+
+func (w *reconnectingWriter) connectLocked() error {
+	conn, err := w.dial()
+	if err != nil {
+		time.Sleep(w.backoff.next(w.attempt))
+		w.attempt++
+		return err
+	}
+	w.conn = conn
+	w.attempt = 0
+	return nil
+}
+
+func (w *reconnectingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	return w.conn.Close()
+}
+
+// Wrap is a thin alias for logger.NewAsyncHandler so callers can plug a
+// netsink handler into an async queue without importing both packages
+// by name.
+func Wrap(h slog.Handler, capacity int, policy logger.OverflowPolicy) *logger.AsyncHandler {
+	return logger.NewAsyncHandler(h, capacity, policy)
+}
+
+// attrSet accumulates the attrs/groups slog.Handler.WithAttrs and
+// WithGroup hand a handler, flattening group-prefixed keys the way
+// slog's built-in handlers do (dot-joined).
+type attrSet struct {
+	groups []string
+	attrs  []slog.Attr
+}
+
+func (s attrSet) withAttrs(attrs []slog.Attr) attrSet {
+	out := attrSet{groups: s.groups}
+	out.attrs = append(append([]slog.Attr{}, s.attrs...), s.prefixed(attrs)...)
+	return out
+}
+
+func (s attrSet) withGroup(name string) attrSet {
+	return attrSet{groups: append(append([]string{}, s.groups...), name), attrs: s.attrs}
+}
+
+func (s attrSet) prefixed(attrs []slog.Attr) []slog.Attr {
+	if len(s.groups) == 0 {
+		return attrs
+	}
+	prefix := strings.Join(s.groups, ".") + "."
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = slog.Attr{Key: prefix + a.Key, Value: a.Value}
+	}
+	return out
+}
+
+func severityOf(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
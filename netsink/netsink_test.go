@@ -0,0 +1,23 @@
+package netsink
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestSeverityOfMapsSlogLevelsToRFC5424Severities(t *testing.T) {
+	cases := []struct {
+		level slog.Level
+		want  int
+	}{
+		{slog.LevelDebug, 7},
+		{slog.LevelInfo, 6},
+		{slog.LevelWarn, 4},
+		{slog.LevelError, 3},
+	}
+	for _, c := range cases {
+		if got := severityOf(c.level); got != c.want {
+			t.Errorf("severityOf(%v) = %d, want %d", c.level, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package netsink
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Facility is an RFC5424 syslog facility code.
+type Facility int
+
+const (
+	FacilityKern   Facility = 0
+	FacilityUser   Facility = 1
+	FacilityDaemon Facility = 3
+	FacilityAuth   Facility = 4
+	FacilityLocal0 Facility = 16
+	FacilityLocal1 Facility = 17
+	FacilityLocal2 Facility = 18
+	FacilityLocal3 Facility = 19
+	FacilityLocal4 Facility = 20
+	FacilityLocal5 Facility = 21
+	FacilityLocal6 Facility = 22
+	FacilityLocal7 Facility = 23
+)
+
+// SyslogOptions configures NewSyslogHandler.
+type SyslogOptions struct {
+	// Network is "udp", "tcp" or "tls".
+	Network string
+	// Addr is the syslog collector address, e.g. "collector:514".
+	Addr string
+	// Facility defaults to FacilityUser.
+	Facility Facility
+	// AppName is the RFC5424 APP-NAME field. Defaults to the process
+	// name.
+	AppName string
+	// Hostname is the RFC5424 HOSTNAME field. Defaults to os.Hostname().
+	Hostname string
+	// TLSConfig is used when Network is "tls".
+	TLSConfig *tls.Config
+	Backoff   BackoffPolicy
+}
+
+// SyslogHandler is a slog.Handler that frames records as RFC5424
+// messages and sends them over UDP, TCP or TLS, reconnecting with
+// exponential backoff on failure.
+type SyslogHandler struct {
+	opts   SyslogOptions
+	writer *reconnectingWriter
+	attrSet
+}
+
+// NewSyslogHandler builds a SyslogHandler. The connection is dialed
+// lazily on the first Handle call.
+func NewSyslogHandler(opts SyslogOptions) *SyslogHandler {
+	if opts.Facility == 0 {
+		opts.Facility = FacilityUser
+	}
+	if opts.AppName == "" {
+		opts.AppName = filepath.Base(os.Args[0])
+	}
+	if opts.Hostname == "" {
+		if h, err := os.Hostname(); err == nil {
+			opts.Hostname = h
+		} else {
+			opts.Hostname = "-"
+		}
+	}
+	dial := func() (net.Conn, error) {
+		if opts.Network == "tls" {
+			return tls.Dial("tcp", opts.Addr, opts.TLSConfig)
+		}
+		return net.Dial(opts.Network, opts.Addr)
+	}
+	return &SyslogHandler{opts: opts, writer: newReconnectingWriter(dial, opts.Backoff)}
+}
+
+func (h *SyslogHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *SyslogHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := append(append([]slog.Attr{}, h.attrs...), recordAttrs(r)...)
+	_, err := h.writer.Write([]byte(formatRFC5424(h.opts, r, attrs)))
+	return err
+}
+
+func (h *SyslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SyslogHandler{opts: h.opts, writer: h.writer, attrSet: h.withAttrs(attrs)}
+}
+
+func (h *SyslogHandler) WithGroup(name string) slog.Handler {
+	return &SyslogHandler{opts: h.opts, writer: h.writer, attrSet: h.withGroup(name)}
+}
+
+// Close closes the underlying connection, whatever state it's in.
+func (h *SyslogHandler) Close() error {
+	return h.writer.Close()
+}
+
+// formatRFC5424 renders r as a single RFC5424 syslog message, carrying
+// attrs as structured data.
+func formatRFC5424(opts SyslogOptions, r slog.Record, attrs []slog.Attr) string {
+	pri := int(opts.Facility)*8 + severityOf(r.Level)
+	sd := "-"
+	if len(attrs) > 0 {
+		var b strings.Builder
+		b.WriteString("[attrs")
+		for _, a := range attrs {
+			fmt.Fprintf(&b, ` %s="%s"`, sdParamName(a.Key), sdParamValue(a.Value.String()))
+		}
+		b.WriteString("]")
+		sd = b.String()
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - %s %s\n",
+		pri,
+		r.Time.UTC().Format(time.RFC3339),
+		opts.Hostname,
+		opts.AppName,
+		os.Getpid(),
+		sd,
+		r.Message,
+	)
+}
+
+// sdParamName strips the characters RFC5424 structured-data param names
+// forbid ('=', ']', '"', space).
+func sdParamName(key string) string {
+	return strings.NewReplacer("=", "_", "]", "_", `"`, "_", " ", "_").Replace(key)
+}
+
+// sdParamValue backslash-escapes the characters RFC5424 requires escaped
+// inside a structured-data param value ('"', '\' and ']'), so a value
+// containing a quote can't terminate the PARAM-VALUE early and corrupt
+// the rest of the message.
+func sdParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+func recordAttrs(r slog.Record) []slog.Attr {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
@@ -0,0 +1,30 @@
+package netsink
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFormatRFC5424EscapesStructuredDataValues(t *testing.T) {
+	opts := SyslogOptions{AppName: "svc", Hostname: "host"}
+	attrs := []slog.Attr{slog.String("query", `say "hi" to \bob]`)}
+
+	msg := formatRFC5424(opts, slog.Record{Message: "test"}, attrs)
+
+	sdStart := strings.Index(msg, "[attrs")
+	sdEnd := strings.Index(msg, "] test")
+	if sdStart < 0 || sdEnd < 0 {
+		t.Fatalf("could not find structured-data block in %q", msg)
+	}
+	sd := msg[sdStart : sdEnd+1]
+	if !strings.Contains(sd, `\"hi\"`) {
+		t.Errorf("expected escaped quotes in structured data, got %q", sd)
+	}
+	if !strings.Contains(sd, `\\bob`) {
+		t.Errorf("expected escaped backslash in structured data, got %q", sd)
+	}
+	if !strings.Contains(sd, `\]`) {
+		t.Errorf("expected escaped closing bracket in structured data, got %q", sd)
+	}
+}
@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingPolicy controls how many records reaching a sink are actually
+// delivered. Every and RateLimit are independent; when both are set a
+// record must survive the every-N check before the rate limiter is
+// consulted.
+type SamplingPolicy struct {
+	// Every keeps 1 out of every N records deterministically. Zero or one
+	// means no deterministic sampling.
+	Every int
+	// RateLimit caps the sustained throughput of the sink using a token
+	// bucket. Nil means no rate limiting.
+	RateLimit *RateLimiter
+}
+
+// allow reports whether the next record passes the sampling policy.
+func (p SamplingPolicy) allow(counter *uint64) bool {
+	if p.Every > 1 {
+		n := atomic.AddUint64(counter, 1)
+		if n%uint64(p.Every) != 0 {
+			return false
+		}
+	}
+	if p.RateLimit != nil && !p.RateLimit.Allow() {
+		return false
+	}
+	return true
+}
+
+// RateLimiter is a simple token-bucket limiter used to cap how many
+// records per second a sink is allowed to receive.
+type RateLimiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	max        float64
+	refillRate float64 // tokens per second
+	last       time.Time
+}
+
+// NewRateLimiter builds a token bucket that allows ratePerSecond records
+// per second on average, bursting up to burst records.
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		tokens:     float64(burst),
+		max:        float64(burst),
+		refillRate: ratePerSecond,
+		last:       time.Now(),
+	}
+}
+
+// Allow consumes a token if one is available.
+func (r *RateLimiter) Allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(r.last).Seconds()
+	r.last = now
+	r.tokens += elapsed * r.refillRate
+	if r.tokens > r.max {
+		r.tokens = r.max
+	}
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// RecordFilter decides whether a record should reach a sink. Returning
+// false drops the record for that sink only.
+type RecordFilter func(slog.Record) bool
+
+// FilterByLevel keeps only records at or above the given level.
+func FilterByLevel(level slog.Leveler) RecordFilter {
+	return func(r slog.Record) bool {
+		return r.Level >= level.Level()
+	}
+}
+
+// FilterByAttr keeps only records carrying an attribute named key whose
+// value satisfies match.
+func FilterByAttr(key string, match func(slog.Value) bool) RecordFilter {
+	return func(r slog.Record) bool {
+		found := false
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == key && match(a.Value) {
+				found = true
+				return false
+			}
+			return true
+		})
+		return found
+	}
+}
+
+// SinkOptions configures how a single sink participates in a Pipeline.
+type SinkOptions struct {
+	// Level is the minimum level delivered to this sink. Defaults to
+	// slog.LevelDebug (everything) when nil.
+	Level slog.Leveler
+	// Filter, if set, must return true for a record to reach this sink.
+	Filter RecordFilter
+	// Sampling thins the stream of records delivered to this sink.
+	Sampling SamplingPolicy
+}
+
+// pipelineSink is a Handler plus the policy deciding which records it sees.
+type pipelineSink struct {
+	name    string
+	handler slog.Handler
+	opts    SinkOptions
+	counter uint64
+}
+
+func (s *pipelineSink) accepts(r slog.Record) bool {
+	if s.opts.Level != nil && r.Level < s.opts.Level.Level() {
+		return false
+	}
+	if s.opts.Filter != nil && !s.opts.Filter(r) {
+		return false
+	}
+	if !s.opts.Sampling.allow(&s.counter) {
+		return false
+	}
+	return true
+}
+
+// Pipeline fans records out to multiple named slog.Handler sinks, each
+// with its own level threshold, attribute filter and sampling policy. It
+// is the common substrate other sinks (syslog, Loki, ...) are plugged
+// into via AddSink.
+type Pipeline struct {
+	mu         sync.Mutex
+	sinks      []*pipelineSink
+	middleware []func(slog.Handler) slog.Handler
+}
+
+// NewPipeline returns an empty Pipeline ready for AddSink/AddMiddleware
+// calls.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// AddSink registers a sink under name with the given options. name is
+// only used for readability/debugging; it does not need to be unique.
+func (p *Pipeline) AddSink(name string, h slog.Handler, opts SinkOptions) *Pipeline {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sinks = append(p.sinks, &pipelineSink{name: name, handler: h, opts: opts})
+	return p
+}
+
+// AddMiddleware registers a handler-wrapping function applied to every
+// sink at Build time, in the order middleware was added.
+func (p *Pipeline) AddMiddleware(fn func(slog.Handler) slog.Handler) *Pipeline {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.middleware = append(p.middleware, fn)
+	return p
+}
+
+// Build wraps every registered sink with the registered middleware and
+// returns a single slog.Handler that fans records out to all of them.
+func (p *Pipeline) Build() slog.Handler {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fh := &fanoutHandler{}
+	for _, s := range p.sinks {
+		h := s.handler
+		for _, mw := range p.middleware {
+			h = mw(h)
+		}
+		fh.sinks = append(fh.sinks, &pipelineSink{name: s.name, handler: h, opts: s.opts})
+	}
+	return fh
+}
+
+// fanoutHandler is the slog.Handler produced by Pipeline.Build.
+type fanoutHandler struct {
+	sinks []*pipelineSink
+}
+
+func (f *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, s := range f.sinks {
+		if s.opts.Level == nil || level >= s.opts.Level.Level() {
+			if s.handler.Enabled(ctx, level) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, s := range f.sinks {
+		if !s.accepts(r) {
+			continue
+		}
+		if err := s.handler.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := &fanoutHandler{}
+	for _, s := range f.sinks {
+		out.sinks = append(out.sinks, &pipelineSink{
+			name:    s.name,
+			handler: s.handler.WithAttrs(attrs),
+			opts:    s.opts,
+		})
+	}
+	return out
+}
+
+func (f *fanoutHandler) WithGroup(name string) slog.Handler {
+	out := &fanoutHandler{}
+	for _, s := range f.sinks {
+		out.sinks = append(out.sinks, &pipelineSink{
+			name:    s.name,
+			handler: s.handler.WithGroup(name),
+			opts:    s.opts,
+		})
+	}
+	return out
+}
+
+// CreateLoggerWithPipeline builds a Logger whose handler is the result of
+// Pipeline.Build, letting callers fan records out to several sinks (e.g.
+// ERROR+ to stderr+file, INFO to a JSON file, sampled DEBUG to a network
+// sink) instead of the single built-in prod/dev handler.
+func CreateLoggerWithPipeline(mod Mod, lType Type, logsDict Path, p *Pipeline) *Logger {
+	logger := build(logsDict, mod, lType)
+	logger.setLogger(slog.New(p.Build()))
+	instance = logger
+	slog.SetDefault(logger.Logger)
+	return logger
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler collects every record handed to it, along with the
+// attrs/groups accumulated via WithAttrs/WithGroup, so tests can assert on
+// what actually reached a sink.
+type recordingHandler struct {
+	mu      *sync.Mutex
+	records *[]slog.Record
+	attrs   []slog.Attr
+	groups  []string
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{mu: &sync.Mutex{}, records: &[]slog.Record{}}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordingHandler{mu: h.mu, records: h.records, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...), groups: h.groups}
+}
+
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	return &recordingHandler{mu: h.mu, records: h.records, attrs: h.attrs, groups: append(append([]string{}, h.groups...), name)}
+}
+
+func (h *recordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(*h.records)
+}
+
+func TestPipelineFansOutWithIndependentLevelAndFilter(t *testing.T) {
+	errSink := newRecordingHandler()
+	infoSink := newRecordingHandler()
+	userSink := newRecordingHandler()
+
+	p := NewPipeline().
+		AddSink("errors", errSink, SinkOptions{Level: slog.LevelError}).
+		AddSink("info", infoSink, SinkOptions{Level: slog.LevelInfo}).
+		AddSink("users", userSink, SinkOptions{
+			Filter: FilterByAttr("user_id", func(v slog.Value) bool { return v.String() != "" }),
+		})
+	handler := p.Build()
+
+	log := func(level slog.Level, attrs ...slog.Attr) {
+		r := slog.NewRecord(time.Now(), level, "msg", 0)
+		r.AddAttrs(attrs...)
+		_ = handler.Handle(context.Background(), r)
+	}
+
+	log(slog.LevelDebug)
+	log(slog.LevelInfo)
+	log(slog.LevelError, slog.String("user_id", "u1"))
+
+	if got := errSink.count(); got != 1 {
+		t.Errorf("errSink: got %d records, want 1", got)
+	}
+	if got := infoSink.count(); got != 2 {
+		t.Errorf("infoSink: got %d records, want 2", got)
+	}
+	if got := userSink.count(); got != 1 {
+		t.Errorf("userSink: got %d records, want 1", got)
+	}
+}
+
+func TestSamplingPolicyEveryNIsDeterministic(t *testing.T) {
+	sink := newRecordingHandler()
+	p := NewPipeline().AddSink("sampled", sink, SinkOptions{Sampling: SamplingPolicy{Every: 3}})
+	handler := p.Build()
+
+	for i := 0; i < 9; i++ {
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+		_ = handler.Handle(context.Background(), r)
+	}
+
+	if got := sink.count(); got != 3 {
+		t.Errorf("Every:3 over 9 records: got %d delivered, want 3", got)
+	}
+}
+
+func TestFanoutHandlerWithAttrsAndWithGroupPropagateToEverySink(t *testing.T) {
+	a := newRecordingHandler()
+	b := newRecordingHandler()
+	p := NewPipeline().AddSink("a", a, SinkOptions{}).AddSink("b", b, SinkOptions{})
+	handler := p.Build()
+
+	derived := handler.WithGroup("req").WithAttrs([]slog.Attr{slog.String("k", "v")})
+
+	fh, ok := derived.(*fanoutHandler)
+	if !ok {
+		t.Fatalf("derived handler is %T, want *fanoutHandler", derived)
+	}
+	for _, s := range fh.sinks {
+		rh, ok := s.handler.(*recordingHandler)
+		if !ok {
+			t.Fatalf("sink %s handler is %T, want *recordingHandler", s.name, s.handler)
+		}
+		if len(rh.groups) != 1 || rh.groups[0] != "req" {
+			t.Errorf("sink %s: groups=%v, want [req]", s.name, rh.groups)
+		}
+		if len(rh.attrs) != 1 || rh.attrs[0].Key != "k" {
+			t.Errorf("sink %s: attrs=%v, want [k=v]", s.name, rh.attrs)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// defaultRedactKeys are the attribute keys RedactingHandler scrubs
+// regardless of value, matched case-insensitively.
+var defaultRedactKeys = []string{
+	"password", "token", "authorization", "api_key", "secret", "cookie", "set-cookie",
+}
+
+var (
+	creditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){13,19}\b`)
+	emailPattern      = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	jwtPattern        = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	awsKeyPattern     = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+)
+
+// defaultRedactPatterns are the regex scrubbers RedactingHandler runs
+// over string attribute values when RedactOptions.Patterns is empty.
+var defaultRedactPatterns = []*regexp.Regexp{creditCardPattern, emailPattern, jwtPattern, awsKeyPattern}
+
+// RedactOptions configures NewRedactingHandler.
+type RedactOptions struct {
+	// Keys are attribute names whose value is always replaced,
+	// regardless of content. Matched case-insensitively. Defaults to
+	// password/token/authorization/api_key/secret/cookie/set-cookie.
+	Keys []string
+	// Patterns are run over every string attribute value not already
+	// caught by Keys. Defaults to credit-card (Luhn-validated), email,
+	// JWT and AWS access key scrubbers.
+	Patterns []*regexp.Regexp
+	// Replacement is substituted for a matched value. Defaults to "***".
+	Replacement string
+	// HashInstead replaces a matched value with a short SHA-256 prefix
+	// instead of Replacement, so operators can still correlate repeated
+	// occurrences of the same secret without seeing it.
+	HashInstead bool
+}
+
+// DefaultRedactOptions returns the RedactOptions CreateProdLogger wires
+// in automatically.
+func DefaultRedactOptions() RedactOptions {
+	return RedactOptions{
+		Keys:        defaultRedactKeys,
+		Patterns:    defaultRedactPatterns,
+		Replacement: "***",
+	}
+}
+
+// RedactingHandler wraps a slog.Handler and scrubs sensitive attribute
+// values, recursing into groups, before delegating.
+type RedactingHandler struct {
+	slog.Handler
+	opts RedactOptions
+	keys map[string]bool
+}
+
+// NewRedactingHandler wraps h with opts. A zero-valued opts behaves like
+// DefaultRedactOptions.
+func NewRedactingHandler(h slog.Handler, opts RedactOptions) *RedactingHandler {
+	if len(opts.Keys) == 0 {
+		opts.Keys = defaultRedactKeys
+	}
+	if len(opts.Patterns) == 0 {
+		opts.Patterns = defaultRedactPatterns
+	}
+	if opts.Replacement == "" {
+		opts.Replacement = "***"
+	}
+	keys := make(map[string]bool, len(opts.Keys))
+	for _, k := range opts.Keys {
+		keys[strings.ToLower(k)] = true
+	}
+	return &RedactingHandler{Handler: h, opts: opts, keys: keys}
+}
+
+func (rh *RedactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(rh.redactAttr(a))
+		return true
+	})
+	return rh.Handler.Handle(ctx, out)
+}
+
+func (rh *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = rh.redactAttr(a)
+	}
+	return &RedactingHandler{Handler: rh.Handler.WithAttrs(redacted), opts: rh.opts, keys: rh.keys}
+}
+
+func (rh *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{Handler: rh.Handler.WithGroup(name), opts: rh.opts, keys: rh.keys}
+}
+
+func (rh *RedactingHandler) redactAttr(a slog.Attr) slog.Attr {
+	if rh.keys[strings.ToLower(a.Key)] {
+		return slog.Attr{Key: a.Key, Value: slog.StringValue(rh.mark(a.Value.String()))}
+	}
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = rh.redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		return slog.Attr{Key: a.Key, Value: slog.StringValue(rh.scrub(a.Value.String()))}
+	default:
+		return a
+	}
+}
+
+// scrub runs every configured pattern over s, leaving non-matches (and,
+// for credit-card candidates, Luhn-invalid numbers) untouched.
+func (rh *RedactingHandler) scrub(s string) string {
+	for _, p := range rh.opts.Patterns {
+		s = p.ReplaceAllStringFunc(s, func(match string) string {
+			if p == creditCardPattern && !luhnValid(match) {
+				return match
+			}
+			return rh.mark(match)
+		})
+	}
+	return s
+}
+
+// mark returns the replacement for a scrubbed value, honoring
+// HashInstead.
+func (rh *RedactingHandler) mark(s string) string {
+	if rh.opts.HashInstead {
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:])[:12]
+	}
+	return rh.opts.Replacement
+}
+
+// luhnValid reports whether s, ignoring spaces and hyphens, is a
+// Luhn-valid numeric string of plausible credit-card length.
+func luhnValid(s string) bool {
+	var digits []int
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, int(c-'0'))
+		case c == ' ' || c == '-':
+			continue
+		default:
+			return false
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
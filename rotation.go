@@ -0,0 +1,344 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RotationPolicy configures RotatingFileWriter. A zero-valued policy
+// rotates only when the strftime-expanded Pattern changes, keeps every
+// backup forever and never compresses.
+type RotationPolicy struct {
+	// MaxSizeBytes rotates the active file once it would exceed this
+	// size. Zero disables size-based rotation.
+	MaxSizeBytes int64
+	// MaxAge removes rotated backups older than this duration. Zero
+	// disables age-based pruning.
+	MaxAge time.Duration
+	// MaxBackups caps how many rotated archives are kept; the oldest are
+	// pruned first. Zero keeps them all.
+	MaxBackups int
+	// Compress gzips rotated archives, appending ".gz" to their name.
+	Compress bool
+	// Pattern is a strftime-style filename, e.g. "service-%Y-%m-%d.log".
+	// It is re-expanded on every rotation check, so a pattern containing
+	// %Y/%m/%d/%H rotates automatically on that time boundary.
+	Pattern string
+	// LinkName, if set, is (re)created as a symlink pointing at the
+	// currently active file after every rotation.
+	LinkName string
+}
+
+// RotatingFileWriter is an io.WriteCloser that rotates its backing file
+// on a size threshold, a time boundary encoded in Pattern, or an
+// explicit Rotate/SIGHUP request, pruning and optionally gzipping
+// archives according to its RotationPolicy.
+type RotatingFileWriter struct {
+	mu        sync.Mutex
+	dir       Path
+	policy    RotationPolicy
+	file      *os.File
+	path      string
+	size      int64
+	openedAt  time.Time
+	sighup    chan os.Signal
+	done      chan struct{}
+	archiveRe *regexp.Regexp
+}
+
+// NewRotatingFileWriter opens (creating if necessary) the file named by
+// policy.Pattern under dir and returns a writer ready for use. It also
+// starts a goroutine that reopens the active file on SIGHUP.
+func NewRotatingFileWriter(dir Path, policy RotationPolicy) (*RotatingFileWriter, error) {
+	if policy.Pattern == "" {
+		policy.Pattern = "service-%Y-%m-%d.log"
+	}
+	w := &RotatingFileWriter{
+		dir:       dir,
+		policy:    policy,
+		done:      make(chan struct{}),
+		archiveRe: archivePattern(policy.Pattern),
+	}
+	if err := os.MkdirAll(dir.String(), os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := w.openLocked(w.targetPath()); err != nil {
+		return nil, err
+	}
+	w.listenSighup()
+	return w, nil
+}
+
+// targetPath expands the strftime-style pattern against the current
+// time.
+func (w *RotatingFileWriter) targetPath() string {
+	return filepath.Join(w.dir.String(), strftime(w.policy.Pattern, time.Now()))
+}
+
+func (w *RotatingFileWriter) openLocked(path string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.path = path
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	w.relink()
+	return nil
+}
+
+func (w *RotatingFileWriter) relink() {
+	if w.policy.LinkName == "" {
+		return
+	}
+	_ = os.Remove(w.policy.LinkName)
+	_ = os.Symlink(w.path, w.policy.LinkName)
+}
+
+// Write implements io.Writer, rotating beforehand if the policy demands
+// it.
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.needsRotateLocked(int64(len(p))) {
+		if err := w.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingFileWriter) needsRotateLocked(next int64) bool {
+	if w.file == nil {
+		return true
+	}
+	if w.policy.MaxSizeBytes > 0 && w.size+next > w.policy.MaxSizeBytes {
+		return true
+	}
+	if target := w.targetPath(); target != w.path {
+		return true
+	}
+	return false
+}
+
+// MaybeRotate rotates the active file if the policy's time boundary has
+// passed, even without an intervening Write. Logger.watcher calls this
+// on a coalesced tick so time-based rotation still happens on quiet
+// loggers.
+func (w *RotatingFileWriter) MaybeRotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.needsRotateLocked(0) {
+		return nil
+	}
+	return w.rotateLocked()
+}
+
+// Rotate forces rotation regardless of size or time, e.g. in response to
+// SIGHUP.
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotateLocked()
+}
+
+// rotateLocked archives the current file (if any), prunes old archives,
+// and opens a fresh active file. Callers must hold w.mu.
+func (w *RotatingFileWriter) rotateLocked() error {
+	oldPath := w.path
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		if oldPath != "" {
+			if err := archive(oldPath, w.policy.Compress); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Size-triggered rotation within the same time window targets the
+	// same path the archive was just moved out of, so this simply opens
+	// a clean file there.
+	target := w.targetPath()
+	if err := w.openLocked(target); err != nil {
+		return err
+	}
+	w.prune()
+	return nil
+}
+
+// archive renames path aside so a fresh file can be opened in its place,
+// gzipping it first when compress is set.
+func archive(path string, compress bool) error {
+	stamped := fmt.Sprintf("%s.%d", path, time.Now().UnixNano())
+	if err := os.Rename(path, stamped); err != nil {
+		return err
+	}
+	if !compress {
+		return nil
+	}
+	return gzipAndRemove(stamped)
+}
+
+func gzipAndRemove(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(out)
+	if _, err := io.Copy(gw, in); err != nil {
+		gw.Close()
+		out.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// prune removes archives beyond MaxBackups and older than MaxAge. The
+// active file (w.path) is never pruned.
+func (w *RotatingFileWriter) prune() {
+	if w.policy.MaxBackups <= 0 && w.policy.MaxAge <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(w.dir.String())
+	if err != nil {
+		return
+	}
+
+	active := filepath.Base(w.path)
+	type archiveFile struct {
+		path string
+		mod  time.Time
+	}
+	var archives []archiveFile
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == active || !w.archiveRe.MatchString(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, archiveFile{path: filepath.Join(w.dir.String(), e.Name()), mod: info.ModTime()})
+	}
+	sort.Slice(archives, func(i, j int) bool { return archives[i].mod.Before(archives[j].mod) })
+
+	if w.policy.MaxAge > 0 {
+		cutoff := time.Now().Add(-w.policy.MaxAge)
+		kept := archives[:0]
+		for _, a := range archives {
+			if a.mod.Before(cutoff) {
+				os.Remove(a.path)
+				continue
+			}
+			kept = append(kept, a)
+		}
+		archives = kept
+	}
+	if w.policy.MaxBackups > 0 && len(archives) > w.policy.MaxBackups {
+		for _, a := range archives[:len(archives)-w.policy.MaxBackups] {
+			os.Remove(a.path)
+		}
+	}
+}
+
+// Close flushes and closes the active file, stopping the SIGHUP
+// listener.
+func (w *RotatingFileWriter) Close() error {
+	close(w.done)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+// strftimeDirective matches the directives strftime/archivePattern
+// understand.
+var strftimeDirective = regexp.MustCompile(`%[YmdHMS]`)
+
+// archivePattern compiles a RotationPolicy.Pattern into a regexp that
+// matches any archive name that pattern could have produced at any past
+// rotation, so prune() keeps working once a time-varying Pattern (e.g.
+// "service-%Y-%m-%d.log") has made the active basename roll past the
+// name an older archive was created under. Each strftime directive
+// becomes \d+; archive() always appends ".<nanos>" and, when
+// Compress is set, ".gz".
+func archivePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	last := 0
+	for _, loc := range strftimeDirective.FindAllStringIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		b.WriteString(`\d+`)
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	b.WriteString(`\.\d+(\.gz)?$`)
+	return regexp.MustCompile("^" + b.String())
+}
+
+// strftime expands the handful of directives RotationPolicy.Pattern
+// supports: %Y %m %d %H %M %S.
+func strftime(pattern string, t time.Time) string {
+	r := strings.NewReplacer(
+		"%Y", t.Format("2006"),
+		"%m", t.Format("01"),
+		"%d", t.Format("02"),
+		"%H", t.Format("15"),
+		"%M", t.Format("04"),
+		"%S", t.Format("05"),
+	)
+	return r.Replace(pattern)
+}
+
+// listenSighup reopens/rotates the active file whenever the process
+// receives SIGHUP, the conventional signal for "reopen your log files".
+func (w *RotatingFileWriter) listenSighup() {
+	w.sighup = make(chan os.Signal, 1)
+	signal.Notify(w.sighup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-w.sighup:
+				_ = w.Rotate()
+			case <-w.done:
+				signal.Stop(w.sighup)
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileWriterPruneMatchesDatedPattern(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRotatingFileWriter(Path(dir+"/"), RotationPolicy{
+		Pattern:    "app-%Y-%m-%d-%H-%M-%S.log",
+		MaxBackups: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	defer w.Close()
+
+	// Simulate 5 past rotations, each producing an archive under a
+	// *different* dated basename the way a real rotation would once the
+	// pattern's %S ticks over.
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		name := fmt.Sprintf("app-%s.log.%d", ts.Format("2006-01-02-15-04-05"), 1000+i)
+		if err := os.WriteFile(dir+"/"+name, []byte("archived"), 0644); err != nil {
+			t.Fatalf("seeding archive %s: %v", name, err)
+		}
+		// Back-date mtimes so prune's oldest-first ordering is meaningful.
+		modTime := ts
+		os.Chtimes(dir+"/"+name, modTime, modTime)
+	}
+
+	activeBase := filepath.Base(w.path)
+
+	w.prune()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	archiveCount := 0
+	activeFound := false
+	for _, e := range entries {
+		if e.Name() == activeBase {
+			activeFound = true
+			continue
+		}
+		if w.archiveRe.MatchString(e.Name()) {
+			archiveCount++
+		}
+	}
+	if !activeFound {
+		t.Error("expected the active log file to survive pruning")
+	}
+	if archiveCount > 2 {
+		t.Errorf("expected at most MaxBackups=2 archives after pruning, got %d", archiveCount)
+	}
+}